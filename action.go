@@ -54,6 +54,7 @@ var noKeyCmds = map[string]bool{
 
 	"AUTH":   true,
 	"ECHO":   true,
+	"HELLO":  true,
 	"PING":   true,
 	"QUIT":   true,
 	"SELECT": true,
@@ -64,6 +65,7 @@ var noKeyCmds = map[string]bool{
 	"OBJECT":    true,
 	"RANDOMKEY": true,
 	"WAIT":      true,
+	"WAITAOF":   true,
 	"SCAN":      true,
 
 	"EVAL":    true,
@@ -175,6 +177,13 @@ func Cmd(rcv interface{}, cmd string, args ...string) CmdAction {
 // FlatCmd does _not_ work for commands whose first parameter isn't a key, or
 // (generally) for MSET. Use Cmd for those.
 //
+// Slices, arrays and maps among the arguments are expanded into one argument
+// per element, with maps expanded into key/value pairs (in no particular
+// order). Structs are expanded into field name/value pairs, using the "redis"
+// field tag as the name if set. Expansion is recursive, so a slice of slices
+// becomes a single flat list. Strings, []byte, numbers, bools and
+// encoding.Text/BinaryMarshalers are always sent as a single argument.
+//
 // FlatCmd supports using a resp.LenReader (an io.Reader with a Len() method) as
 // an argument. *bytes.Buffer is an example of a LenReader, and the resp package
 // has a NewLenReader function which can wrap an existing io.Reader.
@@ -182,6 +191,10 @@ func Cmd(rcv interface{}, cmd string, args ...string) CmdAction {
 // FlatCmd also supports encoding.Text/BinaryMarshalers. It does _not_ currently
 // support resp.Marshaler.
 //
+// bool arguments are always sent as "1" for true and "0" for false, e.g. for
+// SETBIT. Unmarshaling into a bool works the other way around: 0 is false and
+// any positive integer is true.
+//
 // The receiver to FlatCmd follows the same rules as for Cmd.
 func FlatCmd(rcv interface{}, cmd, key string, args ...interface{}) CmdAction {
 	c := getCmdAction()
@@ -212,6 +225,21 @@ func findStreamsKeys(args []string) []string {
 	return nil
 }
 
+// findNumKeysKeys returns the keys from args which start with the number of
+// keys, followed by the keys themselves, e.g. "2 key1 key2 LEFT".
+func findNumKeysKeys(args []string) []string {
+	if len(args) < 1 {
+		return nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 0 {
+		return nil
+	} else if n > len(args)-1 {
+		n = len(args) - 1
+	}
+	return args[1 : n+1]
+}
+
 func (c *cmdAction) Keys() []string {
 	if c.flat {
 		return c.flatKey[:]
@@ -229,6 +257,13 @@ func (c *cmdAction) Keys() []string {
 		return c.args[1:2]
 	} else if cmd == "XREAD" || cmd == "XREADGROUP" { // antirez why you still do this
 		return findStreamsKeys(c.args)
+	} else if cmd == "LMPOP" || cmd == "ZMPOP" {
+		return findNumKeysKeys(c.args)
+	} else if cmd == "BLMPOP" || cmd == "BZMPOP" {
+		if len(c.args) < 1 {
+			return nil
+		}
+		return findNumKeysKeys(c.args[1:])
 	} else if noKeyCmds[cmd] || len(c.args) == 0 {
 		return nil
 	}
@@ -294,6 +329,10 @@ func (c *cmdAction) ClusterCanRetry() bool {
 // being received is a nil RESP type (either bulk string or array), and if so
 // set Nil to true. If not the return value will be unmarshaled into Rcv
 // normally.
+//
+// To detect nil elements within an array, e.g. the reply to an MGET, unmarshal
+// into a slice of pointers instead (e.g. []*string). Nil elements will be left
+// as nil pointers.
 type MaybeNil struct {
 	Nil bool
 	Rcv interface{}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	errors "golang.org/x/xerrors"
 
 	"github.com/mediocregopher/radix/v3/resp/resp2"
 )
@@ -83,6 +84,30 @@ func TestCmdActionStreams(t *T) {
 	require.NoError(t, c.Do(xCmd))
 }
 
+func TestCmdActionMPopKeys(t *T) {
+	for _, test := range []struct {
+		args []string
+		keys []string
+	}{
+		{args: []string{"LMPOP", "2", "a", "b", "LEFT"}, keys: []string{"a", "b"}},
+		{args: []string{"LMPOP", "1", "a", "LEFT", "COUNT", "2"}, keys: []string{"a"}},
+		{args: []string{"ZMPOP", "2", "a", "b", "MIN"}, keys: []string{"a", "b"}},
+		{args: []string{"BLMPOP", "0.5", "2", "a", "b", "RIGHT"}, keys: []string{"a", "b"}},
+		{args: []string{"BZMPOP", "1", "1", "a", "MAX"}, keys: []string{"a"}},
+		{args: []string{"blmpop", "1", "3", "a", "b", "c", "LEFT"}, keys: []string{"a", "b", "c"}},
+
+		// malformed commands shouldn't panic
+		{args: []string{"LMPOP"}, keys: nil},
+		{args: []string{"BLMPOP"}, keys: nil},
+		{args: []string{"BLMPOP", "1"}, keys: nil},
+		{args: []string{"LMPOP", "x", "a"}, keys: nil},
+		{args: []string{"LMPOP", "3", "a"}, keys: []string{"a"}},
+	} {
+		cmd := Cmd(nil, test.args[0], test.args[1:]...)
+		assert.Equal(t, test.keys, cmd.Keys(), "args:%q", test.args)
+	}
+}
+
 func ExampleCmd() {
 	client, err := NewPool("tcp", "127.0.0.1:6379", 10) // or any other client
 	if err != nil {
@@ -130,6 +155,69 @@ func TestFlatCmdActionNil(t *T) {
 	require.False(t, nilVal.Nil)
 }
 
+func TestFlatCmdActionBool(t *T) {
+	var gotArgs []string
+	stub := Stub("tcp", "127.0.0.1:6379", func(args []string) interface{} {
+		gotArgs = args
+		return 1
+	})
+
+	var prev bool
+	require.Nil(t, stub.Do(FlatCmd(&prev, "SETBIT", "foo", 7, true)))
+	assert.Equal(t, []string{"SETBIT", "foo", "7", "1"}, gotArgs)
+	assert.True(t, prev)
+
+	require.Nil(t, stub.Do(FlatCmd(nil, "SETBIT", "foo", 7, false)))
+	assert.Equal(t, []string{"SETBIT", "foo", "7", "0"}, gotArgs)
+
+	require.Nil(t, stub.Do(FlatCmd(nil, "HSET", "foo", map[string]bool{"a": true})))
+	assert.Equal(t, []string{"HSET", "foo", "a", "1"}, gotArgs)
+}
+
+func TestFlatCmdActionFlatten(t *T) {
+	var gotArgs []string
+	stub := Stub("tcp", "127.0.0.1:6379", func(args []string) interface{} {
+		gotArgs = args
+		return nil
+	})
+
+	type testStruct struct {
+		A string `redis:"a"`
+		B int
+	}
+
+	tests := []struct {
+		args []interface{}
+		exp  []string
+	}{
+		{
+			args: []interface{}{[]string{"1", "2"}, 3},
+			exp:  []string{"1", "2", "3"},
+		},
+		{
+			args: []interface{}{[][]int{{1, 2}, {3}}, [2]string{"4", "5"}},
+			exp:  []string{"1", "2", "3", "4", "5"},
+		},
+		{
+			args: []interface{}{[]byte("12"), "3 4"},
+			exp:  []string{"12", "3 4"},
+		},
+		{
+			args: []interface{}{map[string]int{"a": 1}},
+			exp:  []string{"a", "1"},
+		},
+		{
+			args: []interface{}{testStruct{A: "1", B: 2}},
+			exp:  []string{"a", "1", "B", "2"},
+		},
+	}
+
+	for _, test := range tests {
+		require.Nil(t, stub.Do(FlatCmd(nil, "RPUSH", "foo", test.args...)))
+		assert.Equal(t, append([]string{"RPUSH", "foo"}, test.exp...), gotArgs)
+	}
+}
+
 func ExampleFlatCmd() {
 	client, err := NewPool("tcp", "127.0.0.1:6379", 10) // or any other client
 	if err != nil {
@@ -354,7 +442,10 @@ func ExampleWithConn_transaction() {
 			return err
 		}
 
-		// execute the transaction, capturing the result
+		// execute the transaction, capturing the result. If one of the queued
+		// commands failed its error is returned here. To check the reply of
+		// each command individually, unmarshal into a []resp2.RawMessage and
+		// call UnmarshalInto on each element.
 		var result []string
 		if err = c.Do(Cmd(&result, "EXEC")); err != nil {
 			return err
@@ -371,6 +462,99 @@ func ExampleWithConn_transaction() {
 	fmt.Printf("the value of key %q was %q\n", key, prevVal)
 }
 
+func ExampleWithConn_watch() {
+	client, err := NewPool("tcp", "127.0.0.1:6379", 10) // or any other client
+	if err != nil {
+		// handle error
+	}
+
+	// This example performs a compare-and-swap: the value of `key` is set to
+	// newVal only if its current value is oldVal. WATCH is used so that the
+	// transaction is aborted if `key` is modified by someone else in between
+	// the GET and the EXEC, in which case the whole thing is retried.
+	key := "someKey"
+	oldVal, newVal := "someValue", "someOtherValue"
+	var swapped bool
+
+	err = client.Do(WithConn(key, func(c Conn) (err error) {
+		// If any of the calls after the WATCH error it's important that the key
+		// is unwatched, otherwise the Conn goes back into the pool still
+		// watching it and some later, unrelated transaction on it could be
+		// aborted. As with DISCARD, the return from UNWATCH doesn't matter.
+		defer func() {
+			if err != nil {
+				c.Do(Cmd(nil, "UNWATCH"))
+			}
+		}()
+
+		for attempt := 0; attempt < 5; attempt++ {
+			if err = c.Do(Cmd(nil, "WATCH", key)); err != nil {
+				return err
+			}
+
+			var curr string
+			if err = c.Do(Cmd(&curr, "GET", key)); err != nil {
+				return err
+			} else if curr != oldVal {
+				return c.Do(Cmd(nil, "UNWATCH"))
+			}
+
+			if err = c.Do(Cmd(nil, "MULTI")); err != nil {
+				return err
+			} else if err = c.Do(Cmd(nil, "SET", key, newVal)); err != nil {
+				c.Do(Cmd(nil, "DISCARD"))
+				return err
+			}
+
+			// EXEC returns a nil reply if the transaction was aborted due to a
+			// WATCHed key being modified. Either way EXEC unwatches all keys.
+			var result []string
+			mn := MaybeNil{Rcv: &result}
+			if err = c.Do(Cmd(&mn, "EXEC")); err != nil {
+				return err
+			} else if !mn.Nil {
+				swapped = true
+				return nil
+			}
+		}
+		return errors.New("compare-and-swap was aborted too many times")
+	}))
+	if err != nil {
+		// handle error
+	}
+
+	fmt.Printf("value of key %q was swapped: %v\n", key, swapped)
+}
+
+func TestExecCmdErrors(t *T) {
+	// EXEC returns the reply of each queued command, including errors
+	stub := Stub("tcp", "127.0.0.1:6379", func([]string) interface{} {
+		return []interface{}{"OK", errors.New("WRONGTYPE bad"), 5}
+	})
+
+	// unmarshaling into a generic type returns the first error, after having
+	// discarded the rest of the reply
+	var ii []interface{}
+	err := stub.Do(Cmd(&ii, "EXEC"))
+	assert.True(t, errors.As(err, new(resp2.Error)))
+	assert.EqualError(t, err, "WRONGTYPE bad")
+
+	// with RawMessage each reply can be checked individually
+	var rr []resp2.RawMessage
+	require.NoError(t, stub.Do(Cmd(&rr, "EXEC")))
+	require.Len(t, rr, 3)
+	errs := make([]error, len(rr))
+	for i := range rr {
+		errs[i] = rr[i].UnmarshalInto(resp2.Any{})
+	}
+	assert.NoError(t, errs[0])
+	assert.EqualError(t, errs[1], "WRONGTYPE bad")
+	assert.NoError(t, errs[2])
+
+	// the Conn is still usable
+	require.NoError(t, stub.Do(Cmd(&rr, "EXEC")))
+}
+
 func TestMaybeNil(t *T) {
 	mntests := []struct {
 		b     string
@@ -124,9 +124,28 @@ func Expand(b []byte, n int) []byte {
 	return b[:n]
 }
 
+// BufferedLine reads from br until the first \n, returning the line including the \n.
+//
+// Like bufio.Reader.ReadSlice the returned slice normally points into br's buffer and is only valid until the next
+// read from br. Lines which don't fit into br's buffer are supported as well, in which case a newly allocated slice
+// is returned.
+func BufferedLine(br *bufio.Reader) ([]byte, error) {
+	b, err := br.ReadSlice('\n')
+	if err != bufio.ErrBufferFull {
+		return b, err
+	}
+
+	line := append([]byte(nil), b...)
+	for err == bufio.ErrBufferFull {
+		b, err = br.ReadSlice('\n')
+		line = append(line, b...)
+	}
+	return line, err
+}
+
 // BufferedBytesDelim reads a line from br and checks that the line ends with \r\n, returning the line without \r\n.
 func BufferedBytesDelim(br *bufio.Reader) ([]byte, error) {
-	b, err := br.ReadSlice('\n')
+	b, err := BufferedLine(br)
 	if err != nil {
 		return nil, err
 	} else if len(b) < 2 || b[len(b)-2] != '\r' {
@@ -6,7 +6,9 @@ import (
 	crand "crypto/rand"
 	"io"
 	"math/rand"
+	"strings"
 	. "testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
@@ -77,3 +79,27 @@ func TestReadNDiscard(t *T) {
 	// edge cases
 	assert(testT{n: 0, discarder: true})
 }
+
+func TestBufferedLine(t *T) {
+	lines := []string{
+		"\n",
+		"+OK\r\n",
+		strings.Repeat("a", 15) + "\n",
+		strings.Repeat("a", 16) + "\n",
+		strings.Repeat("a", 100) + "\r\n",
+		strings.Repeat("a", 1000) + "\r\n",
+	}
+
+	// bufio.Reader's minimum size is 16, so most of these lines won't fit into
+	// its buffer, and reading one byte at a time ensures no line is ever fully
+	// available in one read either.
+	br := bufio.NewReaderSize(iotest.OneByteReader(strings.NewReader(strings.Join(lines, ""))), 16)
+	for _, line := range lines {
+		b, err := BufferedLine(br)
+		require.NoError(t, err)
+		assert.Equal(t, line, string(b))
+	}
+
+	_, err := BufferedLine(br)
+	assert.Equal(t, io.EOF, err)
+}
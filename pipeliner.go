@@ -11,15 +11,19 @@ import (
 )
 
 var blockingCmds = map[string]bool{
-	"WAIT": true,
+	"WAIT":    true,
+	"WAITAOF": true,
 
 	// taken from https://github.com/joomcode/redispipe#limitations
 	"BLPOP":      true,
 	"BRPOP":      true,
 	"BRPOPLPUSH": true,
+	"BLMOVE":     true,
+	"BLMPOP":     true,
 
 	"BZPOPMIN": true,
 	"BZPOPMAX": true,
+	"BZMPOP":   true,
 
 	"XREAD":      true,
 	"XREADGROUP": true,
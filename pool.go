@@ -41,6 +41,10 @@ func (ioc *ioErrConn) Encode(m resp.Marshaler) error {
 	err := ioc.Conn.Encode(m)
 	if nerr, _ := err.(net.Error); nerr != nil {
 		ioc.lastIOErr = err
+	} else if err != nil && !errors.As(err, new(resp.ErrDiscarded)) {
+		// a partial message may have been written, so the Conn can't be
+		// trusted anymore
+		ioc.lastIOErr = err
 	}
 	return err
 }
@@ -100,7 +104,7 @@ func PoolConnFunc(cf ConnFunc) PoolOpt {
 // each ping event the Pool calls the PING redis command over one of it's
 // available connections.
 //
-// Since connections are used in LIFO order, the ping interval * pool size is
+// Since connections are used in FIFO order, the ping interval * pool size is
 // the duration of time it takes to ping every connection once when the pool is
 // idle.
 //
@@ -228,6 +232,11 @@ func PoolWithTrace(pt trace.PoolTrace) PoolOpt {
 // configured, along with how long they are kept after load has returned to
 // normal.
 //
+// Available connections are used in FIFO order: a connection which is put back
+// into the Pool won't be used again until all the other available connections
+// have been. This spreads load evenly across all of the Pool's connections,
+// rather than concentrating it on a single one.
+//
 // Pool also takes advantage of implicit pipelining. If multiple commands are
 // being performed simultaneously, then Pool will write them all to a single
 // connection using a single system call, and read all their responses together
@@ -270,7 +279,7 @@ type Pool struct {
 // behavior. The default options NewPool uses are:
 //
 //	PoolConnFunc(DefaultConnFunc)
-//	PoolOnEmptyCreateAfter(1 * time.Second)
+//	PoolOnEmptyCreateAfter(1 * time.Second) // 0 if size is 0
 //	PoolRefillInterval(1 * time.Second)
 //	PoolOnFullBuffer((size / 3)+1, 1 * time.Second)
 //	PoolPingInterval(5 * time.Second / (size+1))
@@ -285,7 +294,25 @@ type Pool struct {
 // the size of the pool can be kept low without problems to reduce resource
 // and file descriptor usage.
 //
+// A size of zero is allowed, and means the Pool will not keep any connections
+// open on a permanent basis. Ping and refill events are disabled, and
+// connections are created on demand as per the PoolOnEmpty options. A
+// connection which is put back into a zero sized Pool is kept in the overflow
+// buffer until the next drain event, i.e. for up to a second by default. A
+// negative size is treated the same as zero.
+//
+// Since a zero sized Pool usually has no connection available, its default is
+// PoolOnEmptyCreateAfter(0) rather than 1 second, so that Do never waits for a
+// connection to be put back. If a wait is set explicitly then every Do (or,
+// when implicit pipelining is enabled, every pipeline) made while the overflow
+// connection is busy, or after it has been drained, blocks for that long
+// before a new connection is created.
+//
 func NewPool(network, addr string, size int, opts ...PoolOpt) (*Pool, error) {
+	if size < 0 {
+		size = 0
+	}
+
 	p := &Pool{
 		network:  network,
 		addr:     addr,
@@ -295,9 +322,14 @@ func NewPool(network, addr string, size int, opts ...PoolOpt) (*Pool, error) {
 		ErrCh:    make(chan error, 1),
 	}
 
+	onEmptyWait := 1 * time.Second
+	if size == 0 {
+		onEmptyWait = 0
+	}
+
 	defaultPoolOpts := []PoolOpt{
 		PoolConnFunc(DefaultConnFunc),
-		PoolOnEmptyCreateAfter(1 * time.Second),
+		PoolOnEmptyCreateAfter(onEmptyWait),
 		PoolRefillInterval(1 * time.Second),
 		PoolOnFullBuffer((size/3)+1, 1*time.Second),
 		PoolPingInterval(5 * time.Second / time.Duration(size+1)),
@@ -563,7 +595,8 @@ func (p *Pool) put(ioc *ioErrConn) bool {
 
 // Do implements the Do method of the Client interface by retrieving a Conn out
 // of the pool, calling Run on the given Action with it, and returning the Conn
-// to the pool.
+// to the pool. A Conn is never handed to more than one Action at a time, so
+// replies can't be interleaved between concurrent calls.
 //
 // If the given Action is a CmdAction, it will be pipelined with other concurrent
 // calls to Do, which can improve the performance and resource usage of the Redis
@@ -612,6 +645,16 @@ func (p *Pool) NumAvailConns() int {
 	return len(p.pool)
 }
 
+// NumTotalConns returns the number of connections currently open by the pool,
+// including those which are currently in use. The difference between this and
+// NumAvailConns is the number of connections in use.
+//
+// For more detailed statistics, such as the number of connections created or
+// the time spent in Do, see the PoolWithTrace option.
+func (p *Pool) NumTotalConns() int {
+	return int(atomic.LoadInt64(&p.totalConns))
+}
+
 // Close implements the Close method of the Client
 func (p *Pool) Close() error {
 	p.l.Lock()
@@ -285,6 +285,211 @@ func TestPoolDoDoesNotBlock(t *T) {
 	assert.True(t, timeExceeded == 0)
 }
 
+func TestPoolZeroSize(t *T) {
+	var created int64
+	connFunc := PoolConnFunc(func(string, string) (Conn, error) {
+		atomic.AddInt64(&created, 1)
+		return Stub("tcp", "127.0.0.1:6379", func([]string) interface{} {
+			return nil
+		}), nil
+	})
+
+	newPool := func(size int, opts ...PoolOpt) *Pool {
+		pool, err := NewPool("tcp", "127.0.0.1:6379", size, append([]PoolOpt{connFunc}, opts...)...)
+		require.NoError(t, err)
+		<-pool.initDone
+		return pool
+	}
+
+	t.Run("defaults", func(t *T) {
+		atomic.StoreInt64(&created, 0)
+		pool := newPool(0)
+		defer pool.Close()
+
+		// the initial connection is held in the overflow buffer
+		assert.Equal(t, 1, pool.NumAvailConns())
+		assert.NoError(t, pool.Do(Cmd(nil, "PING")))
+		assert.Equal(t, 1, pool.NumAvailConns())
+		assert.Equal(t, int64(1), atomic.LoadInt64(&created))
+	})
+
+	t.Run("defaultsBusy", func(t *T) {
+		atomic.StoreInt64(&created, 0)
+		pool := newPool(0)
+		defer pool.Close()
+
+		// while the only connection is in use a new one should be created
+		// straight away, rather than waiting for it to be put back
+		assert.NoError(t, pool.Do(WithConn("", func(Conn) error {
+			start := time.Now()
+			assert.NoError(t, pool.Do(WithConn("", func(Conn) error { return nil })))
+			assert.True(t, time.Since(start) < 500*time.Millisecond)
+			return nil
+		})))
+		assert.Equal(t, int64(2), atomic.LoadInt64(&created))
+	})
+
+	t.Run("onFullClose", func(t *T) {
+		atomic.StoreInt64(&created, 0)
+		pool := newPool(0,
+			PoolOnFullClose(),
+			PoolOnEmptyCreateAfter(0),
+			PoolPipelineWindow(0, 0),
+		)
+		defer pool.Close()
+
+		assert.Equal(t, 0, pool.NumAvailConns())
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, pool.Do(Cmd(nil, "PING")))
+			assert.Equal(t, 0, pool.NumAvailConns())
+		}
+		assert.Equal(t, int64(4), atomic.LoadInt64(&created))
+	})
+
+	t.Run("negative", func(t *T) {
+		pool := newPool(-10)
+		defer pool.Close()
+		assert.Equal(t, 0, pool.size)
+		assert.NoError(t, pool.Do(Cmd(nil, "PING")))
+	})
+}
+
+func TestPoolNumTotalConns(t *T) {
+	connFunc := PoolConnFunc(func(string, string) (Conn, error) {
+		return Stub("tcp", "127.0.0.1:6379", func([]string) interface{} {
+			return nil
+		}), nil
+	})
+
+	pool, err := NewPool("tcp", "127.0.0.1:6379", 2, connFunc)
+	require.NoError(t, err)
+	defer pool.Close()
+	<-pool.initDone
+
+	assert.Equal(t, 2, pool.NumTotalConns())
+	assert.Equal(t, 2, pool.NumAvailConns())
+
+	require.NoError(t, pool.Do(WithConn("", func(Conn) error {
+		assert.Equal(t, 2, pool.NumTotalConns())
+		assert.Equal(t, 1, pool.NumTotalConns()-pool.NumAvailConns())
+		return nil
+	})))
+
+	assert.Equal(t, 2, pool.NumTotalConns())
+	assert.Equal(t, pool.NumTotalConns(), pool.NumAvailConns())
+}
+
+func TestPoolFIFO(t *T) {
+	const size = 3
+	var l sync.Mutex
+	var counts []int
+	connFunc := PoolConnFunc(func(string, string) (Conn, error) {
+		l.Lock()
+		defer l.Unlock()
+		i := len(counts)
+		counts = append(counts, 0)
+		return Stub("tcp", "127.0.0.1:6379", func([]string) interface{} {
+			l.Lock()
+			defer l.Unlock()
+			counts[i]++
+			return nil
+		}), nil
+	})
+
+	pool, err := NewPool("tcp", "127.0.0.1:6379", size,
+		connFunc,
+		PoolOnFullClose(),
+		PoolPingInterval(0),
+		PoolRefillInterval(0),
+		PoolPipelineWindow(0, 0),
+	)
+	require.NoError(t, err)
+	defer pool.Close()
+	<-pool.initDone
+
+	for i := 0; i < size*10; i++ {
+		require.NoError(t, pool.Do(Cmd(nil, "PING")))
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	assert.Equal(t, []int{10, 10, 10}, counts)
+}
+
+// exclusiveConn fails the test if Encode or Decode is called on it by more than
+// one go-routine at a time
+type exclusiveConn struct {
+	Conn
+	t     *T
+	inUse int32
+}
+
+func (ec *exclusiveConn) use(fn func() error) error {
+	if !atomic.CompareAndSwapInt32(&ec.inUse, 0, 1) {
+		ec.t.Error("Conn used by two go-routines at the same time")
+	}
+	defer atomic.StoreInt32(&ec.inUse, 0)
+	time.Sleep(10 * time.Microsecond)
+	return fn()
+}
+
+func (ec *exclusiveConn) Encode(m resp.Marshaler) error {
+	return ec.use(func() error { return ec.Conn.Encode(m) })
+}
+
+func (ec *exclusiveConn) Decode(u resp.Unmarshaler) error {
+	return ec.use(func() error { return ec.Conn.Decode(u) })
+}
+
+func TestPoolConnsExclusive(t *T) {
+	connFunc := PoolConnFunc(func(string, string) (Conn, error) {
+		return &exclusiveConn{
+			Conn: Stub("tcp", "127.0.0.1:6379", func(args []string) interface{} {
+				if len(args) > 1 {
+					return args[1]
+				}
+				return nil
+			}),
+			t: t,
+		}, nil
+	})
+
+	pool, err := NewPool("tcp", "127.0.0.1:6379", 2,
+		connFunc,
+		PoolOnEmptyCreateAfter(0),
+		PoolPingInterval(time.Millisecond),
+	)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	// a Conn shared between go-routines would either see overlapping calls to
+	// Encode/Decode, or hand one go-routine the reply meant for another
+	echo := func(c Client) {
+		exp := randStr()
+		var out string
+		assert.NoError(t, c.Do(Cmd(&out, "ECHO", exp)))
+		assert.Equal(t, exp, out)
+	}
+
+	// use both the implicit pipeliner and WithConn, so that Conns are handed out
+	// through every path
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				echo(pool)
+				assert.NoError(t, pool.Do(WithConn("", func(c Conn) error {
+					echo(c)
+					return nil
+				})))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 func TestPoolClose(t *T) {
 	pool := testPool(1)
 	assert.NoError(t, pool.Do(Cmd(nil, "PING")))
@@ -315,4 +520,22 @@ func TestIoErrConn(t *T) {
 		err2 := ioc.Do(Cmd(nil, "GET", randStr()))
 		require.Nil(t, err2)
 	})
+
+	t.Run("ReusableAfterDiscardedEncodeError", func(t *T) {
+		ioc := newIOErrConn(NewConn(&limitedWriteConn{limit: 1 << 20}))
+
+		err := ioc.Encode(FlatCmd(nil, "SET", "foo", make(chan int)))
+		require.True(t, errors.As(err, new(resp.ErrDiscarded)))
+		require.Nil(t, ioc.lastIOErr)
+		require.Nil(t, ioc.Encode(Cmd(nil, "PING")))
+	})
+
+	t.Run("NotReusableAfterPartialWrite", func(t *T) {
+		ioc := newIOErrConn(NewConn(&limitedWriteConn{limit: 5}))
+
+		err := ioc.Encode(Cmd(nil, "SET", "foo", "bar"))
+		require.True(t, errors.Is(err, errWriteLimit))
+		require.Equal(t, err, ioc.lastIOErr)
+		require.Equal(t, err, ioc.Encode(Cmd(nil, "PING")))
+	})
 }
@@ -7,8 +7,10 @@ import (
 )
 
 type persistentPubSubOpts struct {
-	connFn     ConnFunc
-	abortAfter int
+	connFn        ConnFunc
+	abortAfter    int
+	retryInterval time.Duration
+	errCh         chan<- error
 }
 
 // PersistentPubSubOpt is an optional parameter which can be passed into
@@ -37,6 +39,31 @@ func PersistentPubSubAbortAfter(attempts int) PersistentPubSubOpt {
 	}
 }
 
+// PersistentPubSubRetryInterval sets how long PersistentPubSub waits between
+// failed attempts to reconnect.
+func PersistentPubSubRetryInterval(d time.Duration) PersistentPubSubOpt {
+	return func(opts *persistentPubSubOpts) {
+		opts.retryInterval = d
+	}
+}
+
+// PersistentPubSubErrCh takes a channel which asynchronous errors encountered
+// by the PersistentPubSub can be read off of. These include the error which
+// caused a connection to be lost, after which the PersistentPubSub will
+// reconnect and resubscribe, whether that was noticed in the background or by a
+// call to one of its methods. If PersistentPubSubAbortAfter is used then an
+// error from reconnecting in the background is written to the channel as well,
+// while an error from reconnecting during a method call is returned by that
+// call.
+//
+// If the channel blocks the error will be dropped. The channel is never closed
+// by the PersistentPubSub.
+func PersistentPubSubErrCh(errCh chan<- error) PersistentPubSubOpt {
+	return func(opts *persistentPubSubOpts) {
+		opts.errCh = errCh
+	}
+}
+
 type pubSubCmd struct {
 	// msgCh can be set along with one of subscribe/unsubscribe/etc...
 	msgCh                                            chan<- PubSubMessage
@@ -81,6 +108,7 @@ type persistentPubSub struct {
 // default behavior. The default options PersistentPubSubWithOpts uses are:
 //
 //	PersistentPubSubConnFunc(DefaultConnFunc)
+//	PersistentPubSubRetryInterval(200 * time.Millisecond)
 //
 func PersistentPubSubWithOpts(
 	network, addr string, options ...PersistentPubSubOpt,
@@ -88,7 +116,8 @@ func PersistentPubSubWithOpts(
 	PubSubConn, error,
 ) {
 	opts := persistentPubSubOpts{
-		connFn: DefaultConnFunc,
+		connFn:        DefaultConnFunc,
+		retryInterval: 200 * time.Millisecond,
 	}
 	for _, opt := range options {
 		opt(&opts)
@@ -166,7 +195,7 @@ func (p *persistentPubSub) refresh() error {
 		if p.opts.abortAfter > 0 && attempts >= p.opts.abortAfter {
 			return err
 		}
-		time.Sleep(200 * time.Millisecond)
+		time.Sleep(p.opts.retryInterval)
 	}
 }
 
@@ -220,18 +249,28 @@ func (p *persistentPubSub) execCmd(cmd pubSubCmd) error {
 	}
 
 	if err != nil {
+		p.sendErr(err)
 		return p.refresh()
 	}
 	return nil
 }
 
+func (p *persistentPubSub) sendErr(err error) {
+	if p.opts.errCh == nil || err == nil {
+		return
+	}
+	select {
+	case p.opts.errCh <- err:
+	default:
+	}
+}
+
 func (p *persistentPubSub) spin() {
 	for {
 		select {
-		case <-p.currErrCh:
-			// TODO if refresh fails here the user will never know the error. It
-			// would be good to make that error available somehow.
-			p.refresh()
+		case err := <-p.currErrCh:
+			p.sendErr(err)
+			p.sendErr(p.refresh())
 		case cmd := <-p.cmdCh:
 			cmd.resCh <- p.execCmd(cmd)
 			if cmd.close {
@@ -1,11 +1,15 @@
 package radix
 
 import (
+	"sync/atomic"
 	. "testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	errors "golang.org/x/xerrors"
+
+	"github.com/mediocregopher/radix/v3/resp"
 )
 
 func closablePersistentPubSub() (PubSubConn, func()) {
@@ -70,6 +74,124 @@ func TestPersistentPubSubAbortAfter(t *T) {
 	p.Close()
 }
 
+func TestPersistentPubSubErrCh(t *T) {
+	type stub struct {
+		Conn
+		ch chan<- PubSubMessage
+	}
+	stubCh := make(chan stub, 1)
+	var errNope = errors.New("nope")
+	var failDial int32
+	connFn := func(_, _ string) (Conn, error) {
+		if atomic.LoadInt32(&failDial) == 1 {
+			return nil, errNope
+		}
+		conn, ch := PubSubStub("tcp", "127.0.0.1:6379", func([]string) interface{} {
+			return nil
+		})
+		stubCh <- stub{Conn: conn, ch: ch}
+		return conn, nil
+	}
+
+	errCh := make(chan error, 2)
+	p, err := PersistentPubSubWithOpts("", "",
+		PersistentPubSubConnFunc(connFn),
+		PersistentPubSubAbortAfter(1),
+		PersistentPubSubErrCh(errCh))
+	require.NoError(t, err)
+	defer p.Close()
+
+	msgCh := make(chan PubSubMessage, 1)
+	require.NoError(t, p.Subscribe(msgCh, "foo"))
+	s := <-stubCh
+
+	// closing the connection should be reported and the subscription
+	// reinstated on a new connection
+	s.Close()
+	assert.Error(t, <-errCh)
+	s = <-stubCh
+	require.NoError(t, p.Ping()) // wait for resubscribing to complete
+
+	msg := PubSubMessage{Type: "message", Channel: "foo", Message: []byte("bar")}
+	s.ch <- msg
+	assert.Equal(t, msg, <-msgCh)
+
+	// if reconnecting fails that should be reported as well
+	atomic.StoreInt32(&failDial, 1)
+	s.Close()
+	assert.Error(t, <-errCh)
+	assert.Equal(t, errNope, <-errCh)
+}
+
+func TestPersistentPubSubRetryInterval(t *T) {
+	const interval = 50 * time.Millisecond
+	var dialTimes []time.Time
+	connFn := func(_, _ string) (Conn, error) {
+		dialTimes = append(dialTimes, time.Now())
+		if len(dialTimes) < 3 {
+			return nil, errors.New("nope")
+		}
+		conn, _ := PubSubStub("tcp", "127.0.0.1:6379", func([]string) interface{} {
+			return nil
+		})
+		return conn, nil
+	}
+
+	p, err := PersistentPubSubWithOpts("", "",
+		PersistentPubSubConnFunc(connFn),
+		PersistentPubSubRetryInterval(interval))
+	require.NoError(t, err)
+	defer p.Close()
+
+	require.Len(t, dialTimes, 3)
+	for i := 1; i < len(dialTimes); i++ {
+		assert.True(t, dialTimes[i].Sub(dialTimes[i-1]) >= interval)
+	}
+}
+
+// encodeErrConn is a Conn whose Encode fails once failEncode is set, while
+// reads continue to work, so the failure is only noticed when a command is sent
+type encodeErrConn struct {
+	Conn
+	failEncode int32
+}
+
+func (c *encodeErrConn) Encode(m resp.Marshaler) error {
+	if atomic.LoadInt32(&c.failEncode) == 1 {
+		return errors.New("encode failed")
+	}
+	return c.Conn.Encode(m)
+}
+
+func TestPersistentPubSubErrChOnCmd(t *T) {
+	connCh := make(chan *encodeErrConn, 1)
+	connFn := func(_, _ string) (Conn, error) {
+		conn, _ := PubSubStub("tcp", "127.0.0.1:6379", func([]string) interface{} {
+			return nil
+		})
+		c := &encodeErrConn{Conn: conn}
+		connCh <- c
+		return c, nil
+	}
+
+	errCh := make(chan error, 1)
+	p, err := PersistentPubSubWithOpts("", "",
+		PersistentPubSubConnFunc(connFn),
+		PersistentPubSubErrCh(errCh))
+	require.NoError(t, err)
+	defer p.Close()
+
+	require.NoError(t, p.Subscribe(make(chan PubSubMessage), "foo"))
+	c := <-connCh
+
+	// the failure is noticed by Ping, which succeeds after reconnecting, but
+	// the error which caused the reconnect should still be reported
+	atomic.StoreInt32(&c.failEncode, 1)
+	require.NoError(t, p.Ping())
+	assert.EqualError(t, <-errCh, "encode failed")
+	<-connCh
+}
+
 // https://github.com/mediocregopher/radix/issues/184
 func TestPersistentPubSubClose(t *T) {
 	channel := "TestPersistentPubSubClose:" + randStr()
@@ -165,6 +165,7 @@ package radix
 import (
 	"bufio"
 	"crypto/tls"
+	"io"
 	"net"
 	"net/url"
 	"strconv"
@@ -234,8 +235,43 @@ type Conn interface {
 	NetConn() net.Conn
 }
 
+// countingWriter keeps track of how many bytes have been written to the
+// wrapped io.Writer, and the last error encountered doing so.
+type countingWriter struct {
+	io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(b []byte) (int, error) {
+	n, err := cw.Writer.Write(b)
+	cw.n += int64(n)
+	if err != nil {
+		cw.err = err
+	}
+	return n, err
+}
+
+// ReadFrom hands off to the wrapped io.Writer's ReadFrom method if it has one,
+// so that e.g. a *net.TCPConn can still make use of sendfile/splice when
+// writing a resp.LenReader.
+func (cw *countingWriter) ReadFrom(r io.Reader) (int64, error) {
+	rf, ok := cw.Writer.(io.ReaderFrom)
+	if !ok {
+		// hide ReadFrom from io.Copy, otherwise it would call it again
+		return io.Copy(struct{ io.Writer }{cw}, r)
+	}
+	n, err := rf.ReadFrom(r)
+	cw.n += n
+	if err != nil {
+		cw.err = err
+	}
+	return n, err
+}
+
 type connWrap struct {
 	net.Conn
+	w   *countingWriter
 	brw *bufio.ReadWriter
 }
 
@@ -243,9 +279,11 @@ type connWrap struct {
 // of this package. The Read and Write methods on the original net.Conn should
 // not be used after calling this method.
 func NewConn(conn net.Conn) Conn {
+	w := &countingWriter{Writer: conn}
 	return &connWrap{
 		Conn: conn,
-		brw:  bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn)),
+		w:    w,
+		brw:  bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(w)),
 	}
 }
 
@@ -253,11 +291,21 @@ func (cw *connWrap) Do(a Action) error {
 	return a.Run(cw)
 }
 
+// Encode marshals the message into the write buffer and flushes it. If
+// marshaling fails before any of the message has been written to the net.Conn
+// then the buffered part of the message is dropped and the error is wrapped in
+// a resp.ErrDiscarded, as the Conn can continue to be used. Otherwise a partial
+// message may have been written and the Conn should not be used further.
 func (cw *connWrap) Encode(m resp.Marshaler) error {
-	if err := m.MarshalRESP(cw.brw); err != nil {
-		return err
+	n := cw.w.n
+	err := m.MarshalRESP(cw.brw)
+	if err == nil {
+		return cw.brw.Flush()
+	} else if cw.w.n == n && cw.w.err == nil {
+		cw.brw.Writer.Reset(cw.w)
+		return resp.ErrDiscarded{Err: err}
 	}
-	return cw.brw.Flush()
+	return err
 }
 
 func (cw *connWrap) Decode(u resp.Unmarshaler) error {
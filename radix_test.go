@@ -1,15 +1,22 @@
 package radix
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/hex"
+	"io"
+	"net"
 	"regexp"
 	"strings"
 	. "testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	errors "golang.org/x/xerrors"
+
+	"github.com/mediocregopher/radix/v3/resp"
 )
 
 func randStr() string {
@@ -41,6 +48,145 @@ func TestCloseBehavior(t *T) {
 	require.NotNil(t, c.NetConn().SetDeadline(time.Now()))
 }
 
+// shortReadConn is a net.Conn which discards all writes and reads from r
+type shortReadConn struct {
+	net.Conn
+	r io.Reader
+}
+
+func (c *shortReadConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+func (c *shortReadConn) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func TestConnShortReads(t *T) {
+	bulk := strings.Repeat("a", 10000)
+	simple := strings.Repeat("b", 10000)
+	replies := "*3\r\n$10000\r\n" + bulk + "\r\n+OK\r\n:5\r\n" +
+		"+" + simple + "\r\n"
+
+	// every reply arrives one byte at a time, and is larger than the Conn's
+	// read buffer
+	c := NewConn(&shortReadConn{
+		r: iotest.OneByteReader(strings.NewReader(replies)),
+	})
+
+	var arr []string
+	require.NoError(t, c.Do(Cmd(&arr, "LRANGE", "foo", "0", "-1")))
+	assert.Equal(t, []string{bulk, "OK", "5"}, arr)
+
+	var str string
+	require.NoError(t, c.Do(Cmd(&str, "ECHO", simple)))
+	assert.Equal(t, simple, str)
+}
+
+// limitedWriteConn is a net.Conn which accepts up to limit bytes of writes,
+// after which all writes fail with errWriteLimit. Reads return io.EOF.
+type limitedWriteConn struct {
+	net.Conn
+	limit   int
+	written bytes.Buffer
+}
+
+var errWriteLimit = errors.New("write limit reached")
+
+func (c *limitedWriteConn) Read([]byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (c *limitedWriteConn) Write(b []byte) (int, error) {
+	if n := c.limit - c.written.Len(); n < len(b) {
+		c.written.Write(b[:n])
+		return n, errWriteLimit
+	}
+	return c.written.Write(b)
+}
+
+// readerFromConn is a limitedWriteConn which also implements io.ReaderFrom,
+// like *net.TCPConn does, and records whether ReadFrom was used.
+type readerFromConn struct {
+	limitedWriteConn
+	readFrom bool
+}
+
+func (c *readerFromConn) ReadFrom(r io.Reader) (int64, error) {
+	c.readFrom = true
+	return io.Copy(struct{ io.Writer }{c}, r)
+}
+
+func TestConnEncodeErrors(t *T) {
+	// a chan can't be marshaled, so FlatCmd will fail partway through
+	// marshaling its arguments
+	badCmd := func(prefix string) CmdAction {
+		return FlatCmd(nil, "SET", "foo", []interface{}{prefix, make(chan int)})
+	}
+
+	t.Run("marshalErr", func(t *T) {
+		nc := &limitedWriteConn{limit: 1 << 20}
+		c := NewConn(nc)
+
+		err := c.Encode(badCmd(""))
+		assert.True(t, errors.As(err, new(resp.ErrDiscarded)))
+		assert.Zero(t, nc.written.Len())
+
+		// the partially marshaled command must not be sent along with the next
+		require.NoError(t, c.Encode(Cmd(nil, "PING")))
+		assert.Equal(t, "*1\r\n$4\r\nPING\r\n", nc.written.String())
+	})
+
+	t.Run("marshalErrAfterPartialWrite", func(t *T) {
+		nc := &limitedWriteConn{limit: 1 << 20}
+		c := NewConn(nc)
+
+		// the first argument doesn't fit into the write buffer, so some of the
+		// command is written before marshaling fails
+		err := c.Encode(badCmd(strings.Repeat("a", 10000)))
+		assert.Error(t, err)
+		assert.False(t, errors.As(err, new(resp.ErrDiscarded)))
+		assert.NotZero(t, nc.written.Len())
+	})
+
+	t.Run("lenReader", func(t *T) {
+		nc := &readerFromConn{limitedWriteConn: limitedWriteConn{limit: 1 << 20}}
+		c := NewConn(nc)
+
+		// the body is larger than the write buffer, so it should be handed
+		// off to the net.Conn's ReadFrom
+		body := strings.Repeat("a", 10000)
+		lr := func() resp.LenReader {
+			return resp.NewLenReader(strings.NewReader(body), int64(len(body)))
+		}
+		require.NoError(t, c.Encode(FlatCmd(nil, "SET", "foo", lr())))
+		assert.True(t, nc.readFrom)
+		assert.Equal(t, int64(nc.written.Len()), c.(*connWrap).w.n)
+		assert.Equal(t, "*3\r\n$3\r\nSET\r\n$3\r\nfoo\r\n$10000\r\n"+body+"\r\n", nc.written.String())
+
+		// bytes written through ReadFrom count towards the partial write
+		// check, so the Conn isn't mistakenly considered reusable
+		nc = &readerFromConn{limitedWriteConn: limitedWriteConn{limit: 1 << 20}}
+		c = NewConn(nc)
+		err := c.Encode(FlatCmd(nil, "SET", "foo", []interface{}{
+			lr(), make(chan int),
+		}))
+		assert.Error(t, err)
+		assert.True(t, nc.readFrom)
+		assert.False(t, errors.As(err, new(resp.ErrDiscarded)))
+	})
+
+	t.Run("writeErr", func(t *T) {
+		nc := &limitedWriteConn{limit: 5}
+		c := NewConn(nc)
+
+		err := c.Encode(Cmd(nil, "SET", "foo", "bar"))
+		assert.True(t, errors.Is(err, errWriteLimit))
+		assert.False(t, errors.As(err, new(resp.ErrDiscarded)))
+		assert.Equal(t, 5, nc.written.Len())
+	})
+}
+
 func TestDialURI(t *T) {
 	c, err := Dial("tcp", "redis://127.0.0.1:6379")
 	if err != nil {
@@ -28,6 +28,9 @@ type Unmarshaler interface {
 // message. If an error was encountered during unmarshaling but the rest of the
 // message was successfully discarded off of the wire, then the error can be
 // wrapped in this type.
+//
+// Similarly, if an error was encountered while marshaling a message but none of
+// the message was written, then the error can be wrapped in this type.
 type ErrDiscarded struct {
 	Err error
 }
@@ -465,7 +465,9 @@ func discardArrayAfterErr(br *bufio.Reader, left int, err error) error {
 //
 // Most things will be treated as bulk strings, except for those that have their
 // own corresponding type in the RESP protocol (e.g. ints). strings and []bytes
-// will always be encoded as bulk strings, never simple strings.
+// will always be encoded as bulk strings, never simple strings. Floats are
+// encoded in the shortest decimal form which parses back to the same value,
+// without an exponent.
 //
 // Arrays and slices will be treated as RESP arrays, and their values will be
 // treated as if also wrapped in an Any struct. Maps will be similarly treated,
@@ -473,7 +475,15 @@ func discardArrayAfterErr(br *bufio.Reader, left int, err error) error {
 // first.
 //
 // When using UnmarshalRESP the value of I must be a pointer or nil. If it is
-// nil then the RESP value will be read and discarded.
+// nil then the RESP value will be read and discarded. Pointers to pointers,
+// including pointer elements of slices (e.g. []*string), are set to nil when the
+// RESP value is nil, and otherwise allocated as needed. A nil pointer is left
+// nil if unmarshaling into it fails. This can be used to distinguish a nil
+// element of an array from an empty one.
+//
+// Numeric types can be unmarshaled from integer replies as well as from simple
+// and bulk strings containing a number. If the string isn't a number the value
+// of I is set to zero and a resp.ErrDiscarded is returned.
 //
 // If an error type is read in the UnmarshalRESP method then a resp2.Error will
 // be returned with that error, and the value of I won't be touched.
@@ -700,7 +710,7 @@ func (a Any) MarshalRESP(w io.Writer) error {
 
 	switch vv.Kind() {
 	case reflect.Slice, reflect.Array:
-		if vv.IsNil() && !a.MarshalNoArrayHeaders {
+		if vv.Kind() == reflect.Slice && vv.IsNil() && !a.MarshalNoArrayHeaders {
 			_, err := w.Write(nilArray)
 			return err
 		}
@@ -808,6 +818,16 @@ var (
 
 // UnmarshalRESP implements the Unmarshaler method
 func (a Any) UnmarshalRESP(br *bufio.Reader) error {
+	// pointers to pointers, e.g. a **string or the elements of a []*string, are
+	// dereferenced before anything else, so that the inner pointer is treated
+	// like any other receiver
+	if pv, ok := derefPtr(a.I); ok {
+		return a.unmarshalPtr(br, pv)
+	}
+	return a.unmarshal(br)
+}
+
+func (a Any) unmarshal(br *bufio.Reader) error {
 	// if I is itself an Unmarshaler just hit that directly
 	if u, ok := a.I.(resp.Unmarshaler); ok {
 		return u.UnmarshalRESP(br)
@@ -822,8 +842,9 @@ func (a Any) UnmarshalRESP(br *bufio.Reader) error {
 	// This is a super special case that _must_ be handled before we actually
 	// read from the reader. If an *interface{} is given we instead unmarshal
 	// into a default (created based on the type of th message), then set the
-	// *interface{} to that
-	if ai, ok := a.I.(*interface{}); ok {
+	// *interface{} to that. Errors are handled below like for any other type,
+	// they never touch I.
+	if ai, ok := a.I.(*interface{}); ok && prefix != ErrorPrefix[0] {
 		innerA := Any{I: saneDefault(prefix)}
 		if err := innerA.UnmarshalRESP(br); err != nil {
 			return err
@@ -969,6 +990,47 @@ func (a Any) unmarshalSingle(body io.Reader, n int) error {
 	return err
 }
 
+// derefPtr returns the pointer which i points to, if i is a pointer to a
+// pointer.
+func derefPtr(i interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(i)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Ptr {
+		return reflect.Value{}, false
+	}
+	return v.Elem(), true
+}
+
+// unmarshalPtr unmarshals into pv, the pointer which a.I points to. A nil
+// message sets pv to nil. Otherwise if pv is nil then a new value is allocated
+// and unmarshaled into, and pv is only set to it if that succeeds.
+func (a Any) unmarshalPtr(br *bufio.Reader, pv reflect.Value) error {
+	b, err := br.Peek(1)
+	if err != nil {
+		return err
+	}
+
+	switch b[0] {
+	case BulkStringPrefix[0], ArrayPrefix[0]:
+		// both of these are at least 4 bytes long, so peeking 3 can't block
+		if b, err = br.Peek(3); err != nil {
+			return err
+		} else if string(b[1:]) == "-1" {
+			return a.unmarshal(br)
+		}
+	}
+
+	if !pv.IsNil() {
+		return a.cp(pv.Interface()).UnmarshalRESP(br)
+	}
+
+	newV := reflect.New(pv.Type().Elem())
+	if err := a.cp(newV.Interface()).UnmarshalRESP(br); err != nil {
+		return err
+	}
+	pv.Set(newV)
+	return nil
+}
+
 func (a Any) unmarshalNil() error {
 	vv := reflect.ValueOf(a.I)
 	if vv.Kind() != reflect.Ptr || !vv.Elem().CanSet() {
@@ -1232,7 +1294,7 @@ func (rm *RawMessage) UnmarshalRESP(br *bufio.Reader) error {
 }
 
 func (rm *RawMessage) unmarshal(br *bufio.Reader) error {
-	b, err := br.ReadSlice('\n')
+	b, err := bytesutil.BufferedLine(br)
 	if err != nil {
 		return err
 	}
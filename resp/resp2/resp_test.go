@@ -3,9 +3,13 @@ package resp2
 import (
 	"bufio"
 	"bytes"
+	"math"
+	"math/big"
 	"reflect"
 	"strings"
 	. "testing"
+	"testing/iotest"
+	"time"
 
 	errors "golang.org/x/xerrors"
 
@@ -162,6 +166,10 @@ func intPtr(i int) *int {
 	return &i
 }
 
+func strPtr(s string) *string {
+	return &s
+}
+
 type testStructA struct {
 	testStructInner
 	Biz []byte
@@ -200,6 +208,7 @@ func TestAnyMarshal(t *T) {
 	var encodeTests = []encodeTest{
 		// Bulk strings
 		{in: []byte("ohey"), out: "$4\r\nohey\r\n"},
+		{in: []byte("\x00\xff\r\n"), out: "$4\r\n\x00\xff\r\n\r\n"},
 		{in: "ohey", out: "$4\r\nohey\r\n"},
 		{in: "", out: "$0\r\n\r\n"},
 		{in: true, out: "$1\r\n1\r\n"},
@@ -210,7 +219,13 @@ func TestAnyMarshal(t *T) {
 		{in: []byte(nil), forceStr: true, out: "$0\r\n\r\n"},
 		{in: float32(5.5), out: "$3\r\n5.5\r\n"},
 		{in: float64(5.5), out: "$3\r\n5.5\r\n"},
+		{in: float32(0.1), out: "$3\r\n0.1\r\n"},
+		{in: float64(47.11), out: "$5\r\n47.11\r\n"},
+		{in: float64(-0.5), out: "$4\r\n-0.5\r\n"},
+		{in: float64(1e20), out: "$21\r\n100000000000000000000\r\n"},
+		{in: math.Pi, out: "$17\r\n3.141592653589793\r\n"},
 		{in: textCPMarshaler("ohey"), out: "$5\r\nohey_\r\n"},
+		{in: time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC), out: "$20\r\n2009-11-10T23:00:00Z\r\n"},
 		{in: binCPMarshaler("ohey"), out: "$5\r\nohey_\r\n"},
 		{in: "ohey", flat: true, out: "$4\r\nohey\r\n"},
 
@@ -235,6 +250,9 @@ func TestAnyMarshal(t *T) {
 		{in: []int{1, 2}, flat: true, out: ":1\r\n:2\r\n"},
 		{in: []int{1, 2}, forceStr: true, out: "*2\r\n$1\r\n1\r\n$1\r\n2\r\n"},
 		{in: []int{1, 2}, flat: true, forceStr: true, out: "$1\r\n1\r\n$1\r\n2\r\n"},
+		{in: [2]string{"a", "b"}, out: "*2\r\n$1\r\na\r\n$1\r\nb\r\n"},
+		{in: [2]string{"a", "b"}, flat: true, out: "$1\r\na\r\n$1\r\nb\r\n"},
+		{in: [0]int{}, out: "*0\r\n"},
 
 		// Complex arrays
 		{in: []interface{}{}, out: "*0\r\n"},
@@ -355,6 +373,66 @@ func TestAnyMarshal(t *T) {
 	}
 }
 
+func TestAnyFloatRoundTrip(t *T) {
+	floats := []float64{
+		0, 0.1, 47.11, -0.5, 1e20, 1e-20, math.Pi, -math.E,
+		math.MaxFloat64, math.SmallestNonzeroFloat64,
+		math.MaxInt64, 1 << 60,
+	}
+
+	for _, f := range floats {
+		buf := new(bytes.Buffer)
+		require.NoError(t, Any{I: f}.MarshalRESP(buf))
+		assert.NotContains(t, buf.String(), "e", "f:%v", f)
+
+		var out float64
+		require.NoError(t, Any{I: &out}.UnmarshalRESP(bufio.NewReader(buf)))
+		assert.Equal(t, f, out)
+
+		// float32s should round-trip as float32s, not gain float64 precision
+		f32 := float32(f)
+		if math.IsInf(float64(f32), 0) {
+			continue
+		}
+		buf.Reset()
+		require.NoError(t, Any{I: f32}.MarshalRESP(buf))
+
+		var out32 float32
+		require.NoError(t, Any{I: &out32}.UnmarshalRESP(bufio.NewReader(buf)))
+		assert.Equal(t, f32, out32)
+	}
+}
+
+func TestAnyBigNumbers(t *T) {
+	const bigIntStr = "-123456789012345678901234567890"
+	bigInt, _ := new(big.Int).SetString(bigIntStr, 10)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, Any{I: bigInt}.MarshalRESP(buf))
+	assert.Equal(t, "$31\r\n"+bigIntStr+"\r\n", buf.String())
+
+	outInt := new(big.Int)
+	require.NoError(t, Any{I: outInt}.UnmarshalRESP(bufio.NewReader(buf)))
+	assert.Zero(t, bigInt.Cmp(outInt), "got %v", outInt)
+
+	// integer replies work too
+	require.NoError(t, Any{I: outInt}.UnmarshalRESP(bufio.NewReader(bytes.NewBufferString(":-5\r\n"))))
+	assert.Equal(t, int64(-5), outInt.Int64())
+
+	bigFloat, _, err := big.ParseFloat("12345678901234567890.5", 10, 128, big.ToNearestEven)
+	require.NoError(t, err)
+
+	buf.Reset()
+	require.NoError(t, Any{I: bigFloat}.MarshalRESP(buf))
+	// big.Float marshals using the 'g' format, which is exact but may use an
+	// exponent
+	assert.Equal(t, "$26\r\n1.23456789012345678905e+19\r\n", buf.String())
+
+	outFloat := new(big.Float).SetPrec(128)
+	require.NoError(t, Any{I: outFloat}.UnmarshalRESP(bufio.NewReader(buf)))
+	assert.Zero(t, bigFloat.Cmp(outFloat), "got %v", outFloat)
+}
+
 type textCPUnmarshaler []byte
 
 func (cu *textCPUnmarshaler) UnmarshalText(b []byte) error {
@@ -427,15 +505,22 @@ func TestAnyUnmarshal(t *T) {
 			{in: "$0\r\n\r\n", out: []byte(nil)},
 			{in: "$4\r\nohey\r\n", out: "ohey"},
 			{in: "$4\r\nohey\r\n", out: []byte("ohey")},
+			{in: "$4\r\n\x00\xff\r\n\r\n", out: []byte("\x00\xff\r\n")},
+			{in: "$4\r\n\x00\xff\r\n\r\n", out: "\x00\xff\r\n"},
 			{in: "$4\r\nohey\r\n", preload: []byte(nil), out: []byte("ohey")},
 			{in: "$4\r\nohey\r\n", preload: []byte(""), out: []byte("ohey")},
 			{in: "$4\r\nohey\r\n", preload: []byte("wut"), out: []byte("ohey")},
 			{in: "$4\r\nohey\r\n", preload: []byte("wutwut"), out: []byte("ohey")},
 			{in: "$4\r\nohey\r\n", out: textCPUnmarshaler("ohey")},
+			{in: "$20\r\n2009-11-10T23:00:00Z\r\n", out: time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)},
 			{in: "$4\r\nohey\r\n", out: binCPUnmarshaler("ohey")},
 			{in: "$4\r\nohey\r\n", out: writer("ohey")},
 			{in: "$2\r\n10\r\n", out: int(10)},
 			{in: "$2\r\n10\r\n", out: uint(10)},
+			{in: "$2\r\n10\r\n", out: int64(10)},
+			{in: "$3\r\n-10\r\n", out: int(-10)},
+			{in: "$1\r\n1\r\n", out: true},
+			{in: "$1\r\n0\r\n", out: false},
 			{in: "$4\r\n10.5\r\n", out: float32(10.5)},
 			{in: "$4\r\n10.5\r\n", out: float64(10.5)},
 			{in: "$4\r\nohey\r\n", preloadEmpty: true, out: []byte("ohey")},
@@ -459,6 +544,7 @@ func TestAnyUnmarshal(t *T) {
 			// Err
 			{in: "-ohey\r\n", out: "", shouldErr: "ohey"},
 			{in: "-ohey\r\n", out: nil, shouldErr: "ohey"},
+			{in: "-ohey\r\n", preloadEmpty: true, shouldErr: "ohey"},
 
 			// Int
 			{in: ":1024\r\n", out: "1024"},
@@ -468,6 +554,11 @@ func TestAnyUnmarshal(t *T) {
 			{in: ":1024\r\n", out: writer("1024")},
 			{in: ":1024\r\n", out: int(1024)},
 			{in: ":1024\r\n", out: uint(1024)},
+			{in: ":-1024\r\n", out: int(-1024)},
+			{in: ":-1024\r\n", out: "-1024"},
+			{in: ":1\r\n", out: true},
+			{in: ":1024\r\n", preload: false, out: true},
+			{in: ":0\r\n", preload: true, out: false},
 			{in: ":1024\r\n", out: float32(1024)},
 			{in: ":1024\r\n", out: float64(1024)},
 			{in: ":1024\r\n", preloadEmpty: true, out: int64(1024)},
@@ -501,6 +592,7 @@ func TestAnyUnmarshal(t *T) {
 				out: []interface{}{[]interface{}{"foo", "bar"}, "baz"},
 			},
 			{in: "*2\r\n:1\r\n:2\r\n", out: map[string]string{"1": "2"}},
+			{in: "*3\r\n:1\r\n:0\r\n:1\r\n", out: []bool{true, false, true}},
 			{in: "*2\r\n*2\r\n+foo\r\n+bar\r\n*1\r\n+baz\r\n", out: nil},
 			{
 				in: "*6\r\n" +
@@ -523,6 +615,30 @@ func TestAnyUnmarshal(t *T) {
 				},
 			},
 
+			// Pointers
+			{in: "$-1\r\n", out: (*string)(nil)},
+			{in: "$-1\r\n", preload: strPtr("wut"), out: (*string)(nil)},
+			{in: "$0\r\n\r\n", out: strPtr("")},
+			{in: "$4\r\nohey\r\n", out: strPtr("ohey")},
+			{in: "+ohey\r\n", preload: strPtr("wut"), out: strPtr("ohey")},
+			{in: ":1024\r\n", out: intPtr(1024)},
+			{in: "$4\r\nohey\r\n", out: func() *textCPUnmarshaler { u := textCPUnmarshaler("ohey"); return &u }()},
+			{in: "*-1\r\n", out: (*[]string)(nil)},
+			{in: "*2\r\n+foo\r\n+bar\r\n", out: &[]string{"foo", "bar"}},
+			{
+				in:  "*3\r\n$1\r\na\r\n$0\r\n\r\n$-1\r\n",
+				out: []*string{strPtr("a"), strPtr(""), nil},
+			},
+			{
+				in:  "*4\r\n$1\r\na\r\n$-1\r\n$1\r\nb\r\n$0\r\n\r\n",
+				out: map[string]*string{"a": nil, "b": strPtr("")},
+			},
+			{
+				in:  "*3\r\n$1\r\na\r\n$1\r\nb\r\n$-1\r\n",
+				out: []*BulkString{{S: "a"}, {S: "b"}, nil},
+			},
+			{in: ":5\r\n", out: func() *interface{} { var i interface{} = int64(5); return &i }()},
+
 			// Arrays (structs)
 			{
 				in: "*10\r\n" +
@@ -612,6 +728,53 @@ func TestAnyUnmarshal(t *T) {
 			assertUnmarshal(br, dt)
 		}
 	}
+
+	// do all the writes first, then read them back one byte at a time through
+	// the smallest possible buffer, so that no message is ever available in a
+	// single read
+	{
+		buf := new(bytes.Buffer)
+		for _, dt := range decodeTests() {
+			buf.WriteString(dt.in)
+		}
+		br := bufio.NewReaderSize(iotest.OneByteReader(buf), 16)
+		for _, dt := range decodeTests() {
+			assertUnmarshal(br, dt)
+		}
+	}
+}
+
+func TestUnmarshalLongLines(t *T) {
+	long := strings.Repeat("a", 8192)
+
+	{
+		br := bufio.NewReader(strings.NewReader("+" + long + "\r\n"))
+		var s string
+		require.NoError(t, Any{I: &s}.UnmarshalRESP(br))
+		assert.Equal(t, long, s)
+	}
+
+	{
+		br := bufio.NewReader(strings.NewReader("+" + long + "\r\n"))
+		var ss SimpleString
+		require.NoError(t, ss.UnmarshalRESP(br))
+		assert.Equal(t, long, ss.S)
+	}
+
+	{
+		br := bufio.NewReader(strings.NewReader("-" + long + "\r\n"))
+		err := Any{}.UnmarshalRESP(br)
+		require.Error(t, err)
+		assert.Equal(t, long, err.Error())
+	}
+
+	{
+		in := "*2\r\n+" + long + "\r\n-" + long + "\r\n"
+		br := bufio.NewReader(strings.NewReader(in))
+		var rm RawMessage
+		require.NoError(t, rm.UnmarshalRESP(br))
+		assert.Equal(t, in, string(rm))
+	}
 }
 
 func TestRawMessage(t *T) {
@@ -684,6 +847,15 @@ func TestAnyConsumedOnErr(t *T) {
 		{BulkString{S: "bulkStr"}, new(unknownType)},
 		{SimpleString{S: "bulkStr"}, new(unknownType)},
 		{Int{I: 1}, new(unknownType)},
+		{Any{I: errors.New("foo")}, new(interface{})},
+		{Any{I: []interface{}{"OK", errors.New("foo"), 1}}, new([]interface{})},
+		{Any{I: []interface{}{"OK", errors.New("foo"), 1}}, new(interface{})},
+		{BulkString{S: "ohey"}, new(int)},
+		{BulkString{S: ""}, new(int)},
+		{SimpleString{S: "ohey"}, new(int)},
+		{BulkString{S: "-1"}, new(uint)},
+		{BulkString{S: "ohey"}, new(*int)},
+		{Any{I: []string{"1", "ohey"}}, new([]*int)},
 		{Any{I: []string{"one", "2", "three"}}, new([]int)},
 		{Any{I: []string{"1", "2", "three", "four"}}, new([]int)},
 		{Any{I: []string{"1", "2", "3", "four"}}, new([]int)},
@@ -715,6 +887,32 @@ func TestAnyConsumedOnErr(t *T) {
 	}
 }
 
+func TestAnyUnmarshalPtrOnErr(t *T) {
+	// a nil pointer must not be allocated if unmarshaling into it fails
+	unmarshal := func(in resp.Marshaler, into interface{}) error {
+		buf := new(bytes.Buffer)
+		require.NoError(t, in.MarshalRESP(buf))
+		return Any{I: into}.UnmarshalRESP(bufio.NewReader(buf))
+	}
+
+	var i *int
+	assert.Error(t, unmarshal(BulkString{S: "ohey"}, &i))
+	assert.Nil(t, i)
+
+	var ii []*int
+	assert.Error(t, unmarshal(Any{I: []string{"1", "ohey"}}, &ii))
+	assert.Equal(t, []*int{intPtr(1), nil}, ii)
+
+	var bb []*BulkString
+	assert.Error(t, unmarshal(Any{I: []interface{}{"a", errors.New("foo")}}, &bb))
+	assert.Equal(t, []*BulkString{{S: "a"}, nil}, bb)
+
+	// a non-nil pointer is unmarshaled into in place, like any other receiver
+	b := &BulkString{S: "wut"}
+	assert.Error(t, unmarshal(Any{I: errors.New("foo")}, &b))
+	assert.Equal(t, &BulkString{S: "wut"}, b)
+}
+
 func TestErrorAs(t *T) {
 	{
 		err := Error{E: errors.New("foo")}